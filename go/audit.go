@@ -0,0 +1,123 @@
+// audit.go: structured JSONL audit sidecar (--audit / KAHL_AUDIT_FD)
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditEvent describes one redaction for the audit sidecar: enough for
+// downstream tooling (SIEM, CI gates) to count/aggregate findings without
+// re-parsing the human-readable redacted stream.
+type AuditEvent struct {
+	Ts        string  `json:"ts"`
+	File      string  `json:"file,omitempty"` // set by "kahl scan"; empty for the stdin filter
+	Line      int     `json:"line"`
+	Col       int     `json:"col"`
+	Len       int     `json:"len"`
+	Label     string  `json:"label"`
+	Source    string  `json:"source"` // "pattern", "value", or "entropy"
+	Structure string  `json:"structure"`
+	Entropy   float64 `json:"entropy,omitempty"`
+	Charset   string  `json:"charset,omitempty"`
+}
+
+// recordAudit appends an AuditEvent to events, if a sink was requested.
+func recordAudit(events *[]AuditEvent, lineNum, col, length int, label, source, structure string, entropy float64, charset string) {
+	if events == nil {
+		return
+	}
+	*events = append(*events, AuditEvent{
+		Ts:        time.Now().UTC().Format(time.RFC3339Nano),
+		Line:      lineNum,
+		Col:       col,
+		Len:       length,
+		Label:     label,
+		Source:    source,
+		Structure: structure,
+		Entropy:   entropy,
+		Charset:   charset,
+	})
+}
+
+// AuditFlags holds the opt-in --audit / KAHL_AUDIT_FD configuration. The
+// flag takes precedence over the env var, matching this tool's existing
+// CLI-over-environment precedence.
+type AuditFlags struct {
+	Path string
+	FD   int // 0 means unset; fd 0 is stdin and is never a valid sidecar sink
+}
+
+// parseAuditFlags parses --audit=<path>/--audit <path>, falling back to
+// KAHL_AUDIT_FD=<fd> (for pipelines that hand the filter a pre-opened
+// descriptor rather than a path) when no flag is given.
+func parseAuditFlags(args []string) AuditFlags {
+	flags := AuditFlags{}
+	for i, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "--audit="):
+			flags.Path = strings.TrimPrefix(arg, "--audit=")
+		case arg == "--audit" && i+1 < len(args):
+			flags.Path = args[i+1]
+		}
+	}
+	if flags.Path == "" {
+		if val := os.Getenv("KAHL_AUDIT_FD"); val != "" {
+			if n, err := strconv.Atoi(val); err == nil {
+				flags.FD = n
+			}
+		}
+	}
+	return flags
+}
+
+// openAuditSink opens the sidecar sink described by flags. A zero-value
+// AuditFlags is not an error; it simply means no sidecar was requested.
+func openAuditSink(flags AuditFlags) (io.WriteCloser, error) {
+	if flags.Path != "" {
+		f, err := os.Create(flags.Path)
+		if err != nil {
+			return nil, fmt.Errorf("opening audit sidecar %s: %w", flags.Path, err)
+		}
+		return f, nil
+	}
+	if flags.FD != 0 {
+		return os.NewFile(uintptr(flags.FD), "kahl-audit-fd"), nil
+	}
+	return nil, nil
+}
+
+// auditWriter serializes AuditEvents to the configured sink as JSONL, one
+// object per redaction, as they occur. Events are flushed line-by-line
+// rather than batched until EOF so the sidecar stays in lockstep with the
+// redacted stdout stream kahl is piping in parallel.
+type auditWriter struct {
+	enc *json.Encoder
+}
+
+// newAuditWriter wraps w, or returns nil if w is nil (no sidecar requested).
+// A nil *auditWriter's methods are no-ops, so callers don't need to guard
+// every call site on whether auditing is enabled.
+func newAuditWriter(w io.Writer) *auditWriter {
+	if w == nil {
+		return nil
+	}
+	return &auditWriter{enc: json.NewEncoder(w)}
+}
+
+// flush writes events to the sink. A write failure is swallowed rather than
+// aborting the filter: a broken audit pipe shouldn't stop secrets from being
+// redacted on stdout.
+func (a *auditWriter) flush(events []AuditEvent) {
+	if a == nil {
+		return
+	}
+	for _, ev := range events {
+		_ = a.enc.Encode(ev)
+	}
+}
@@ -0,0 +1,18 @@
+// blockdetectors_gen.go: compiled-in BlockDetector registry.
+//
+// Mirrors patterns_gen.go's builtin-table style: the detectors themselves
+// are hand-written (see blockdetect.go), this file just lists which are
+// active and in what order. Order matters where two detectors could match
+// the same line -- e.g. opensshPrivateKeyDetector is listed ahead of
+// pemPrivateKeyDetector so an OpenSSH key gets the more specific label
+// rather than the generic PEM one.
+package main
+
+var blockDetectors = []BlockDetector{
+	opensshPrivateKeyDetector{},
+	pemPrivateKeyDetector{},
+	pgpMessageDetector{},
+	jwtWrappedDetector{},
+	kubeconfigClientKeyDetector{},
+	jsonServiceAccountDetector{},
+}
@@ -0,0 +1,475 @@
+// config.go: layered config-file support for kahl, with SIGHUP hot reload.
+//
+// Precedence (highest wins): CLI flags > SECRETS_FILTER_*/KAHL_* env vars >
+// config file. The config file itself is resolved in this order: --config
+// flag, $KAHL_CONFIG, ./.kahl.yaml, $XDG_CONFIG_HOME/kahl/config.yaml (or
+// ~/.config/kahl/config.yaml), /etc/kahl/config.yaml.
+//
+// kahl ships with no third-party dependencies, so the file is parsed with
+// the same small hand-rolled YAML subset used elsewhere in this project's
+// tooling (top-level sections, "- " list items, one level of nested
+// maps); it is not a general YAML or TOML parser.
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// configMu guards every table that applyKahlConfig can rebuild: patterns,
+// contextPatterns, EntropyExclusions, EntropyThresholds, EntropyMinLength,
+// EntropyMaxLength, MaxPrivateKeyBuffer, LongThreshold, the compiled
+// exclusion cache, and currentFilterDefaults. The stdin-filtering loop in
+// main() and the SIGHUP handler goroutine both touch these concurrently.
+var configMu sync.RWMutex
+
+// currentFilterDefaults is the filter-mode baseline parseFilterConfig
+// starts from, before CLI/env overrides are applied. It defaults to
+// defaultFilterConfig and may be overridden by the "filter:" config
+// section.
+var currentFilterDefaults = defaultFilterConfig
+
+// literalPatternMatcher is the Aho-Corasick automaton over whichever active
+// patterns are plain literal strings, rebuilt by applyKahlConfig alongside
+// patterns/contextPatterns. Guarded by configMu like the other tables it's
+// derived from.
+var literalPatternMatcher *Matcher
+
+// KahlConfig is the full user-supplied configuration, merged over the
+// compiled-in defaults from patterns_gen.go.
+type KahlConfig struct {
+	Filter              FilterOverrides
+	EntropyMinLength    int
+	EntropyMaxLength    int
+	EntropyThresholds   map[string]float64
+	MaxPrivateKeyBuffer int
+	Rules               []UserPattern
+	Exclusions          []UserExclusion
+}
+
+// FilterOverrides holds config-file filter-mode overrides; a nil field
+// means "not set in the config file, defer to the compiled-in default".
+type FilterOverrides struct {
+	Values   *bool
+	Patterns *bool
+	Entropy  *bool
+}
+
+// UserPattern describes one user-defined detection rule. Group of 0 means
+// the whole match is the secret (like patterns); Group > 0 names a capture
+// group to redact, with ContextKeywords as a comma-separated list (like
+// contextPatterns).
+type UserPattern struct {
+	Name            string
+	Regex           string
+	Label           string
+	Group           int
+	ContextKeywords string
+}
+
+// UserExclusion describes one user-defined entropy exclusion.
+type UserExclusion struct {
+	Pattern         string
+	Label           string
+	CaseInsensitive bool
+	ContextKeywords string
+}
+
+// kahlConfigPathFromArgs looks for --config <path> or --config=<path>.
+func kahlConfigPathFromArgs(args []string) string {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "--config=") {
+			return strings.TrimPrefix(arg, "--config=")
+		}
+		if arg == "--config" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// resolveKahlConfigPath applies the documented precedence: --config flag,
+// then $KAHL_CONFIG, then ./.kahl.yaml, then
+// $XDG_CONFIG_HOME/kahl/config.yaml (or ~/.config/kahl/config.yaml), then
+// /etc/kahl/config.yaml.
+func resolveKahlConfigPath(args []string) string {
+	if p := kahlConfigPathFromArgs(args); p != "" {
+		return p
+	}
+	if p := os.Getenv("KAHL_CONFIG"); p != "" {
+		return p
+	}
+	if _, err := os.Stat(".kahl.yaml"); err == nil {
+		return ".kahl.yaml"
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		if p := filepath.Join(xdg, "kahl", "config.yaml"); fileExists(p) {
+			return p
+		}
+	} else if home, err := os.UserHomeDir(); err == nil {
+		if p := filepath.Join(home, ".config", "kahl", "config.yaml"); fileExists(p) {
+			return p
+		}
+	}
+	if fileExists("/etc/kahl/config.yaml") {
+		return "/etc/kahl/config.yaml"
+	}
+	return ""
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// loadKahlConfig reads and parses the config file at path. A blank path
+// (no file found or configured) is not an error; it simply means defaults
+// only.
+func loadKahlConfig(path string) (*KahlConfig, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+	return parseKahlYAML(data)
+}
+
+// parseKahlYAML parses the restricted YAML subset described above.
+func parseKahlYAML(data []byte) (*KahlConfig, error) {
+	cfg := &KahlConfig{EntropyThresholds: map[string]float64{}}
+	var section string
+	var curRule *UserPattern
+	var curExcl *UserExclusion
+
+	flush := func() {
+		if curRule != nil {
+			cfg.Rules = append(cfg.Rules, *curRule)
+			curRule = nil
+		}
+		if curExcl != nil {
+			cfg.Exclusions = append(cfg.Exclusions, *curExcl)
+			curExcl = nil
+		}
+	}
+
+	for _, raw := range strings.Split(string(data), "\n") {
+		line := stripKahlYAMLComment(raw)
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		trimmed := strings.TrimSpace(line)
+
+		if indent == 0 {
+			flush()
+			section = strings.TrimSuffix(trimmed, ":")
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") {
+			item := strings.TrimPrefix(trimmed, "- ")
+			switch section {
+			case "rules":
+				flush()
+				curRule = &UserPattern{}
+				applyRuleField(curRule, item)
+			case "exclusions":
+				flush()
+				curExcl = &UserExclusion{}
+				applyExclusionField(curExcl, item)
+			}
+			continue
+		}
+
+		if curRule != nil {
+			applyRuleField(curRule, trimmed)
+			continue
+		}
+		if curExcl != nil {
+			applyExclusionField(curExcl, trimmed)
+			continue
+		}
+
+		switch section {
+		case "filter":
+			applyFilterField(&cfg.Filter, trimmed)
+		case "entropy":
+			if strings.HasSuffix(trimmed, ":") {
+				section = "entropy.thresholds"
+				continue
+			}
+			applyEntropyField(cfg, trimmed)
+		case "entropy.thresholds":
+			applyThresholdField(cfg, trimmed)
+		case "buffer":
+			applyBufferField(cfg, trimmed)
+		}
+	}
+	flush()
+
+	return cfg, nil
+}
+
+// splitKahlYAMLField parses a "key: value" line.
+func splitKahlYAMLField(field string) (key, val string, ok bool) {
+	parts := strings.SplitN(field, ":", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	return strings.TrimSpace(parts[0]), unquoteKahlYAML(strings.TrimSpace(parts[1])), true
+}
+
+func applyFilterField(f *FilterOverrides, field string) {
+	key, val, ok := splitKahlYAMLField(field)
+	if !ok {
+		return
+	}
+	b := isTruthy(val)
+	switch key {
+	case "values":
+		f.Values = &b
+	case "patterns":
+		f.Patterns = &b
+	case "entropy":
+		f.Entropy = &b
+	}
+}
+
+func applyEntropyField(cfg *KahlConfig, field string) {
+	key, val, ok := splitKahlYAMLField(field)
+	if !ok {
+		return
+	}
+	switch key {
+	case "min_length":
+		if n, err := strconv.Atoi(val); err == nil {
+			cfg.EntropyMinLength = n
+		}
+	case "max_length":
+		if n, err := strconv.Atoi(val); err == nil {
+			cfg.EntropyMaxLength = n
+		}
+	}
+}
+
+func applyThresholdField(cfg *KahlConfig, field string) {
+	key, val, ok := splitKahlYAMLField(field)
+	if !ok {
+		return
+	}
+	if f, err := strconv.ParseFloat(val, 64); err == nil {
+		cfg.EntropyThresholds[key] = f
+	}
+}
+
+func applyBufferField(cfg *KahlConfig, field string) {
+	key, val, ok := splitKahlYAMLField(field)
+	if !ok || key != "max_private_key_lines" {
+		return
+	}
+	if n, err := strconv.Atoi(val); err == nil {
+		cfg.MaxPrivateKeyBuffer = n
+	}
+}
+
+func applyRuleField(r *UserPattern, field string) {
+	key, val, ok := splitKahlYAMLField(field)
+	if !ok {
+		return
+	}
+	switch key {
+	case "name":
+		r.Name = val
+	case "regex":
+		r.Regex = val
+	case "label":
+		r.Label = val
+	case "group":
+		if n, err := strconv.Atoi(val); err == nil {
+			r.Group = n
+		}
+	case "context_keywords":
+		r.ContextKeywords = val
+	}
+}
+
+func applyExclusionField(e *UserExclusion, field string) {
+	key, val, ok := splitKahlYAMLField(field)
+	if !ok {
+		return
+	}
+	switch key {
+	case "pattern":
+		e.Pattern = val
+	case "label":
+		e.Label = val
+	case "case_insensitive":
+		e.CaseInsensitive = isTruthy(val)
+	case "context_keywords":
+		e.ContextKeywords = val
+	}
+}
+
+// stripKahlYAMLComment removes a trailing "# ..." comment, ignoring '#'
+// inside quotes.
+func stripKahlYAMLComment(line string) string {
+	inQuote := byte(0)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if inQuote != 0 {
+			if c == inQuote {
+				inQuote = 0
+			}
+			continue
+		}
+		if c == '"' || c == '\'' {
+			inQuote = c
+		} else if c == '#' {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// unquoteKahlYAML strips a single layer of matching quotes, if present.
+func unquoteKahlYAML(s string) string {
+	if len(s) >= 2 {
+		if (s[0] == '"' && s[len(s)-1] == '"') || (s[0] == '\'' && s[len(s)-1] == '\'') {
+			return s[1 : len(s)-1]
+		}
+	}
+	return s
+}
+
+func splitContextKeywords(csv string) []string {
+	if csv == "" {
+		return nil
+	}
+	var out []string
+	for _, kw := range strings.Split(csv, ",") {
+		if kw = strings.TrimSpace(kw); kw != "" {
+			out = append(out, kw)
+		}
+	}
+	return out
+}
+
+// applyKahlConfig rebuilds every table applyKahlConfig controls from the
+// compiled-in builtins plus cfg, and swaps them into the active package
+// vars under configMu. Called once at startup and again on every SIGHUP.
+func applyKahlConfig(cfg *KahlConfig) {
+	newPatterns := append([]Pattern(nil), builtinPatterns...)
+	newContext := append([]ContextPattern(nil), builtinContextPatterns...)
+	newExclusions := append([]EntropyExclusion(nil), builtinEntropyExclusions...)
+	newThresholds := copyFloatMap(builtinEntropyThresholds)
+	minLen := builtinEntropyMinLength
+	maxLen := builtinEntropyMaxLength
+	bufSize := builtinMaxPrivateKeyBuffer
+	filterDefaults := defaultFilterConfig
+
+	if cfg != nil {
+		if cfg.Filter.Values != nil {
+			filterDefaults.ValuesEnabled = *cfg.Filter.Values
+		}
+		if cfg.Filter.Patterns != nil {
+			filterDefaults.PatternsEnabled = *cfg.Filter.Patterns
+		}
+		if cfg.Filter.Entropy != nil {
+			filterDefaults.EntropyEnabled = *cfg.Filter.Entropy
+		}
+
+		for _, up := range cfg.Rules {
+			if up.Regex == "" {
+				continue
+			}
+			re, err := regexp.Compile(up.Regex)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "kahl: skipping rule %q: %v\n", up.Name, err)
+				continue
+			}
+			label := up.Label
+			if label == "" {
+				label = strings.ToUpper(up.Name)
+			}
+			if up.Group > 0 {
+				newContext = append(newContext, ContextPattern{Regex: re, Label: label, Group: up.Group})
+			} else {
+				newPatterns = append(newPatterns, Pattern{Regex: re, Label: label})
+			}
+		}
+
+		for _, ue := range cfg.Exclusions {
+			if ue.Pattern == "" {
+				continue
+			}
+			newExclusions = append(newExclusions, EntropyExclusion{
+				Pattern:         ue.Pattern,
+				CaseInsensitive: ue.CaseInsensitive,
+				Label:           ue.Label,
+				ContextKeywords: splitContextKeywords(ue.ContextKeywords),
+			})
+		}
+
+		for charset, t := range cfg.EntropyThresholds {
+			newThresholds[charset] = t
+		}
+		if cfg.EntropyMinLength > 0 {
+			minLen = cfg.EntropyMinLength
+		}
+		if cfg.EntropyMaxLength > 0 {
+			maxLen = cfg.EntropyMaxLength
+		}
+		if cfg.MaxPrivateKeyBuffer > 0 {
+			bufSize = cfg.MaxPrivateKeyBuffer
+		}
+	}
+
+	regexPatterns, newLiteralMatcher := splitLiteralPatterns(newPatterns)
+
+	configMu.Lock()
+	patterns = regexPatterns
+	literalPatternMatcher = newLiteralMatcher
+	contextPatterns = newContext
+	EntropyExclusions = newExclusions
+	EntropyThresholds = newThresholds
+	EntropyMinLength = minLen
+	EntropyMaxLength = maxLen
+	MaxPrivateKeyBuffer = bufSize
+	currentFilterDefaults = filterDefaults
+	// Force getCompiledExclusions to recompile against the new EntropyExclusions.
+	compiledExclusions = nil
+	exclusionsCompiled = false
+	configMu.Unlock()
+}
+
+// watchConfigReload re-reads the config file on SIGHUP and rebuilds the
+// pattern/exclusion tables in place. The stdin-reading loop in main()
+// keeps running unaffected; it always reads the active tables through
+// configMu, so a reload never drops or corrupts in-flight lines.
+func watchConfigReload(path string) {
+	if path == "" {
+		return
+	}
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go func() {
+		for range sighup {
+			cfg, err := loadKahlConfig(path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "kahl: config reload: %v\n", err)
+				continue
+			}
+			applyKahlConfig(cfg)
+			fmt.Fprintf(os.Stderr, "kahl: reloaded config from %s\n", path)
+		}
+	}()
+}
@@ -0,0 +1,99 @@
+// gitignore.go: a practical subset of .gitignore matching for "scan" mode
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gitignorePattern is one parsed line of a .gitignore file.
+type gitignorePattern struct {
+	pattern string
+	dirOnly bool
+	base    string // directory the pattern is relative to
+}
+
+// gitignoreSet holds the patterns collected from each scan root's
+// .gitignore. This implements a practical subset of gitignore: glob
+// segments and directory-relative matching are supported, but '**',
+// negation ('!'), and nested .gitignore files deeper in the tree are not.
+type gitignoreSet struct {
+	patterns []gitignorePattern
+}
+
+// loadGitignores reads the .gitignore at each scan root, if present.
+func loadGitignores(roots []string) *gitignoreSet {
+	return loadIgnoreFiles(roots, ".gitignore")
+}
+
+// loadIgnoreFiles is like loadGitignores but reads every name in names at
+// each root, in order -- e.g. "kahl scan" also honors a .kahlignore
+// alongside .gitignore.
+func loadIgnoreFiles(roots []string, names ...string) *gitignoreSet {
+	set := &gitignoreSet{}
+	for _, root := range roots {
+		dir := root
+		if info, err := os.Stat(root); err == nil && !info.IsDir() {
+			dir = filepath.Dir(root)
+		}
+		for _, name := range names {
+			set.loadFile(filepath.Join(dir, name), dir)
+		}
+	}
+	return set
+}
+
+func (s *gitignoreSet) loadFile(path, base string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(strings.TrimRight(line, "\r"))
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		dirOnly := strings.HasSuffix(trimmed, "/")
+		pattern := strings.TrimSuffix(trimmed, "/")
+		s.patterns = append(s.patterns, gitignorePattern{pattern: pattern, dirOnly: dirOnly, base: base})
+	}
+}
+
+// matches reports whether path (a file, or a directory when isDir is true)
+// should be ignored.
+func (s *gitignoreSet) matches(path string, isDir bool) bool {
+	if s == nil {
+		return false
+	}
+	for _, p := range s.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		rel, err := filepath.Rel(p.base, path)
+		if err != nil {
+			continue
+		}
+		if matchGitignorePattern(p.pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGitignorePattern matches a single gitignore pattern against rel. A
+// pattern containing a slash is matched against the whole relative path;
+// one without a slash matches the base name at any depth.
+func matchGitignorePattern(pattern, rel string) bool {
+	rel = filepath.ToSlash(rel)
+	if strings.Contains(pattern, "/") {
+		ok, _ := filepath.Match(pattern, rel)
+		return ok
+	}
+	for _, seg := range strings.Split(rel, "/") {
+		if ok, _ := filepath.Match(pattern, seg); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,399 @@
+// kahl_scan.go: "kahl scan" subcommand -- walks a working tree (or the
+// staged diff, for a pre-commit/lefthook hook) and reports findings from
+// the same three engines runFilter's stdin loop uses (redactEnvValues,
+// redactPatterns, redactEntropy), instead of filtering a stream.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// KahlScanFlags holds "kahl scan"'s options.
+type KahlScanFlags struct {
+	Paths    []string
+	Staged   bool
+	Format   string // "text" (default) or "sarif"
+	Severity string // minimum severity (see scanSeverity) that fails the scan
+}
+
+// parseKahlScanFlags parses the "scan" subcommand's args: everything that
+// isn't a recognized flag is treated as a path to scan.
+func parseKahlScanFlags(args []string) KahlScanFlags {
+	flags := KahlScanFlags{Format: "text", Severity: "low"}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--staged":
+			flags.Staged = true
+		case strings.HasPrefix(arg, "--format="):
+			flags.Format = strings.TrimPrefix(arg, "--format=")
+		case arg == "--format" && i+1 < len(args):
+			flags.Format = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--severity="):
+			flags.Severity = strings.TrimPrefix(arg, "--severity=")
+		case arg == "--severity" && i+1 < len(args):
+			flags.Severity = args[i+1]
+			i++
+		default:
+			if !strings.HasPrefix(arg, "-") {
+				flags.Paths = append(flags.Paths, arg)
+			}
+		}
+	}
+	return flags
+}
+
+// runKahlScan implements "kahl scan [paths...]" / "kahl scan --staged".
+func runKahlScan(args []string) {
+	flags := parseKahlScanFlags(args)
+	redact := parseRedactFlags(args)
+
+	gitRoot, gitDir := findGitRoot(".")
+
+	var files []string
+	if flags.Staged {
+		staged, err := stagedFiles(gitDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "kahl: %v\n", err)
+			os.Exit(2)
+		}
+		files = staged
+	} else {
+		paths := flags.Paths
+		if len(paths) == 0 {
+			paths = []string{"."}
+		}
+		ignoreRoots := append([]string{}, paths...)
+		if gitRoot != "" {
+			ignoreRoots = append(ignoreRoots, gitRoot)
+		}
+		ignore := loadIgnoreFiles(ignoreRoots, ".gitignore", ".kahlignore")
+		for _, root := range paths {
+			files = append(files, walkKahlScanTargets(root, ignore)...)
+		}
+	}
+
+	secrets := loadSecrets()
+
+	var events []AuditEvent
+	for _, path := range files {
+		fileEvents, err := scanKahlFile(path, secrets, redact)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "kahl: %s: %v\n", path, err)
+			continue
+		}
+		events = append(events, fileEvents...)
+	}
+
+	var err error
+	switch flags.Format {
+	case "sarif":
+		err = writeKahlSARIF(os.Stdout, events)
+	default:
+		printKahlScanReport(events)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "kahl: %v\n", err)
+		os.Exit(2)
+	}
+
+	if hasKahlFindingAtOrAbove(events, flags.Severity) {
+		os.Exit(1)
+	}
+}
+
+// findGitRoot walks up from start looking for a ".git" entry, honoring
+// GIT_DIR when set (as a pre-commit hook invocation may do). root is the
+// working-tree directory the scan should treat as its ignore-file base;
+// gitDir is the resolved .git path, passed through to "git diff" for
+// --staged. Both are "" if no repository was found.
+func findGitRoot(start string) (root, gitDir string) {
+	if gd := os.Getenv("GIT_DIR"); gd != "" {
+		abs, err := filepath.Abs(gd)
+		if err != nil {
+			abs = gd
+		}
+		return filepath.Dir(abs), abs
+	}
+
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", ""
+	}
+	for {
+		candidate := filepath.Join(dir, ".git")
+		if _, err := os.Stat(candidate); err == nil {
+			return dir, candidate
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// stagedFiles lists the added/copied/modified paths in the index, for
+// "kahl scan --staged" run from a pre-commit hook.
+func stagedFiles(gitDir string) ([]string, error) {
+	cmd := exec.Command("git", "diff", "--cached", "--name-only", "--diff-filter=ACM")
+	if gitDir != "" {
+		cmd.Env = append(os.Environ(), "GIT_DIR="+gitDir)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing staged files: %w", err)
+	}
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// walkKahlScanTargets walks root, returning files that survive .gitignore
+// and .kahlignore.
+func walkKahlScanTargets(root string, ignore *gitignoreSet) []string {
+	var files []string
+
+	info, err := os.Stat(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "kahl: %s: %v\n", root, err)
+		return nil
+	}
+	if !info.IsDir() {
+		files = append(files, root)
+		return files
+	}
+
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if ignore.matches(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ignore.matches(path, false) {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+
+	return files
+}
+
+// scanKahlFile runs all three redaction engines over path's lines, via the
+// same redactLine main() uses, returning the AuditEvents it produced.
+func scanKahlFile(path string, secrets map[string]string, redact RedactFlags) ([]AuditEvent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if looksBinaryKahl(data) {
+		return nil, nil
+	}
+
+	config := FilterConfig{ValuesEnabled: true, PatternsEnabled: true, EntropyEnabled: true}
+
+	var events []AuditEvent
+	lineNum := 0
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lineNum++
+		redactLine(scanner.Text(), secrets, config, lineNum, &events, redact)
+	}
+	if err := scanner.Err(); err != nil {
+		return events, err
+	}
+
+	for i := range events {
+		events[i].File = path
+	}
+	return events, nil
+}
+
+// looksBinaryKahl applies the same null-byte-plus-content-sniff heuristic
+// "secrets-filter scan" uses, so "kahl scan" doesn't try to line-scan
+// binary blobs.
+func looksBinaryKahl(data []byte) bool {
+	if len(data) > 512 {
+		data = data[:512]
+	}
+	for _, b := range data {
+		if b == 0 {
+			return true
+		}
+	}
+	contentType := http.DetectContentType(data)
+	return !strings.HasPrefix(contentType, "text/")
+}
+
+// scanSeverity classifies an AuditEvent for --severity gating. kahl has no
+// general severity taxonomy, so entropy-only hits (more prone to false
+// positives than an explicit pattern/value match) are "low" and everything
+// else is "high".
+func scanSeverity(ev AuditEvent) string {
+	if ev.Source == "entropy" {
+		return "low"
+	}
+	return "high"
+}
+
+var kahlSeverityRank = map[string]int{"low": 0, "high": 1}
+
+// hasKahlFindingAtOrAbove reports whether any event's severity meets or
+// exceeds min, so runKahlScan knows whether to exit non-zero.
+func hasKahlFindingAtOrAbove(events []AuditEvent, min string) bool {
+	threshold, ok := kahlSeverityRank[min]
+	if !ok {
+		threshold = kahlSeverityRank["low"]
+	}
+	for _, ev := range events {
+		if kahlSeverityRank[scanSeverity(ev)] >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// printKahlScanReport prints a diff-style report to stdout: a "---" header
+// per file (echoing a unified diff's file header) followed by one
+// line:col finding per redaction. The secret values themselves are never
+// printed, only their labels and shapes.
+func printKahlScanReport(events []AuditEvent) {
+	if len(events) == 0 {
+		fmt.Println("kahl: no findings")
+		return
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].File != events[j].File {
+			return events[i].File < events[j].File
+		}
+		if events[i].Line != events[j].Line {
+			return events[i].Line < events[j].Line
+		}
+		return events[i].Col < events[j].Col
+	})
+
+	lastFile := ""
+	for _, ev := range events {
+		if ev.File != lastFile {
+			fmt.Printf("--- %s\n", ev.File)
+			lastFile = ev.File
+		}
+		fmt.Printf("  %d:%d  %s (%s)\n", ev.Line, ev.Col+1, ev.Label, ev.Structure)
+	}
+	fmt.Fprintf(os.Stderr, "kahl: %d finding(s)\n", len(events))
+}
+
+// SARIF 2.1.0 document types for "kahl scan --format=sarif", kept minimal
+// to what kahl emits.
+type kahlSarifLog struct {
+	Schema  string         `json:"$schema"`
+	Version string         `json:"version"`
+	Runs    []kahlSarifRun `json:"runs"`
+}
+
+type kahlSarifRun struct {
+	Tool    kahlSarifTool     `json:"tool"`
+	Results []kahlSarifResult `json:"results"`
+}
+
+type kahlSarifTool struct {
+	Driver kahlSarifDriver `json:"driver"`
+}
+
+type kahlSarifDriver struct {
+	Name  string          `json:"name"`
+	Rules []kahlSarifRule `json:"rules"`
+}
+
+type kahlSarifRule struct {
+	ID string `json:"id"`
+}
+
+type kahlSarifResult struct {
+	RuleID    string              `json:"ruleId"`
+	Message   kahlSarifMessage    `json:"message"`
+	Locations []kahlSarifLocation `json:"locations"`
+}
+
+type kahlSarifMessage struct {
+	Text string `json:"text"`
+}
+
+type kahlSarifLocation struct {
+	PhysicalLocation kahlSarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type kahlSarifPhysicalLocation struct {
+	ArtifactLocation kahlSarifArtifactLocation `json:"artifactLocation"`
+	Region           kahlSarifRegion           `json:"region"`
+}
+
+type kahlSarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type kahlSarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+// writeKahlSARIF renders events as a single-run SARIF 2.1.0 log, with
+// tool.driver.rules enumerating the distinct labels seen, suitable for
+// upload to GitHub code scanning.
+func writeKahlSARIF(w *os.File, events []AuditEvent) error {
+	seen := map[string]bool{}
+	var rules []kahlSarifRule
+	var results []kahlSarifResult
+	for _, ev := range events {
+		if !seen[ev.Label] {
+			seen[ev.Label] = true
+			rules = append(rules, kahlSarifRule{ID: ev.Label})
+		}
+		results = append(results, kahlSarifResult{
+			RuleID:  ev.Label,
+			Message: kahlSarifMessage{Text: fmt.Sprintf("Detected %s (%s)", ev.Label, ev.Structure)},
+			Locations: []kahlSarifLocation{{
+				PhysicalLocation: kahlSarifPhysicalLocation{
+					ArtifactLocation: kahlSarifArtifactLocation{URI: ev.File},
+					Region:           kahlSarifRegion{StartLine: ev.Line, StartColumn: ev.Col + 1},
+				},
+			}},
+		})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	doc := kahlSarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []kahlSarifRun{{
+			Tool:    kahlSarifTool{Driver: kahlSarifDriver{Name: "kahl", Rules: rules}},
+			Results: results,
+		}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
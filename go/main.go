@@ -76,8 +76,12 @@ func describeStructure(s string) string {
 		return ""
 	}
 
+	configMu.RLock()
+	longThreshold := LongThreshold
+	configMu.RUnlock()
+
 	// Very long tokens
-	if len(s) >= LongThreshold {
+	if len(s) >= longThreshold {
 		for _, sep := range []string{"-", "_", "."} {
 			if strings.Contains(s, sep) {
 				parts := strings.Split(s, sep)
@@ -161,9 +165,18 @@ func loadSecrets() map[string]string {
 		}
 	}
 
+	// Build the Aho-Corasick automaton redactEnvValues scans against, so a
+	// line is walked once regardless of how many secrets were loaded.
+	secretMatcher = buildSecretMatcher(secrets)
+
 	return secrets
 }
 
+// secretMatcher is the active automaton built by loadSecrets over the
+// current secret set. redactEnvValues falls back to the old per-secret
+// strings.Index loop when it's nil (e.g. no secrets loaded).
+var secretMatcher *Matcher
+
 // isFalsy checks if a string represents a falsy boolean value
 func isFalsy(val string) bool {
 	lower := strings.ToLower(strings.TrimSpace(val))
@@ -176,13 +189,13 @@ func isTruthy(val string) bool {
 	return lower == "1" || lower == "true" || lower == "yes"
 }
 
-// parseFilterConfig parses filter configuration from CLI args and environment
+// parseFilterConfig parses filter configuration from CLI args and
+// environment, starting from currentFilterDefaults (the compiled-in
+// baseline, possibly overridden by a "filter:" config-file section).
 func parseFilterConfig() FilterConfig {
-	config := FilterConfig{
-		ValuesEnabled:   true,
-		PatternsEnabled: true,
-		EntropyEnabled:  false, // Entropy is off by default
-	}
+	configMu.RLock()
+	config := currentFilterDefaults
+	configMu.RUnlock()
 
 	// Check for --version or -v
 	args := os.Args[1:]
@@ -273,12 +286,31 @@ func parseFilterConfig() FilterConfig {
 }
 
 // redactEnvValues replaces known secret values with [REDACTED:VAR_NAME:structure]
-func redactEnvValues(text string, secrets map[string]string) string {
+func redactEnvValues(text string, secrets map[string]string, lineNum int, events *[]AuditEvent, redact RedactFlags) string {
 	if secrets == nil {
 		return text
 	}
 
-	// Sort by value length descending
+	if secretMatcher != nil {
+		matches := secretMatcher.Scan(text)
+		if len(matches) == 0 {
+			return text
+		}
+		var out strings.Builder
+		last := 0
+		for _, match := range matches {
+			structure := describeStructure(match.Value)
+			recordAudit(events, lineNum, match.Start, match.End-match.Start, match.Key, "value", structure, 0, "")
+			out.WriteString(text[last:match.Start])
+			out.WriteString(withHash("[REDACTED:"+match.Key+":"+structure+"]", match.Value, redact))
+			last = match.End
+		}
+		out.WriteString(text[last:])
+		return out.String()
+	}
+
+	// Fallback for when secretMatcher wasn't built (e.g. no secrets loaded):
+	// sort by value length descending and replace each in turn.
 	type kv struct {
 		key string
 		val string
@@ -295,55 +327,101 @@ func redactEnvValues(text string, secrets map[string]string) string {
 
 	for _, s := range sorted {
 		structure := describeStructure(s.val)
-		replacement := "[REDACTED:" + s.key + ":" + structure + "]"
-		text = strings.ReplaceAll(text, s.val, replacement)
+		replacement := withHash("[REDACTED:"+s.key+":"+structure+"]", s.val, redact)
+		for {
+			idx := strings.Index(text, s.val)
+			if idx == -1 {
+				break
+			}
+			recordAudit(events, lineNum, idx, len(s.val), s.key, "value", structure, 0, "")
+			text = text[:idx] + replacement + text[idx+len(s.val):]
+		}
 	}
 
 	return text
 }
 
 // redactPatterns replaces known token patterns
-func redactPatterns(text string) string {
+func redactPatterns(text string, lineNum int, events *[]AuditEvent, redact RedactFlags) string {
+	configMu.RLock()
+	activePatterns := patterns
+	activeContextPatterns := contextPatterns
+	activeLiteralMatcher := literalPatternMatcher
+	configMu.RUnlock()
+
+	// Literal-string patterns (regex with no metacharacters) are matched by
+	// the same Aho-Corasick automaton redactEnvValues uses, rather than a
+	// full regex scan; literalPatternMatcher never contains a pattern also
+	// present in activePatterns, so this can't double-redact.
+	if activeLiteralMatcher != nil {
+		matches := activeLiteralMatcher.Scan(text)
+		for i := len(matches) - 1; i >= 0; i-- {
+			match := matches[i]
+			structure := describeStructure(match.Value)
+			recordAudit(events, lineNum, match.Start, match.End-match.Start, match.Key, "pattern", structure, 0, "")
+			marker := withHash("[REDACTED:"+match.Key+":"+structure+"]", match.Value, redact)
+			text = text[:match.Start] + marker + text[match.End:]
+		}
+	}
+
 	// Direct patterns
-	for _, p := range patterns {
-		text = p.Regex.ReplaceAllStringFunc(text, func(match string) string {
+	for _, p := range activePatterns {
+		locs := p.Regex.FindAllStringIndex(text, -1)
+		for i := len(locs) - 1; i >= 0; i-- {
+			start, end := locs[i][0], locs[i][1]
+			match := text[start:end]
 			structure := describeStructure(match)
-			return "[REDACTED:" + p.Label + ":" + structure + "]"
-		})
+			recordAudit(events, lineNum, start, end-start, p.Label, "pattern", structure, 0, "")
+			marker := withHash("[REDACTED:"+p.Label+":"+structure+"]", match, redact)
+			text = text[:start] + marker + text[end:]
+		}
 	}
 
 	// Context patterns (simulate lookbehind)
-	for _, cp := range contextPatterns {
-		text = cp.Regex.ReplaceAllStringFunc(text, func(match string) string {
-			submatches := cp.Regex.FindStringSubmatch(match)
-			if len(submatches) > cp.Group {
-				secret := submatches[cp.Group]
-				structure := describeStructure(secret)
-				return submatches[1] + "[REDACTED:" + cp.Label + ":" + structure + "]"
+	for _, cp := range activeContextPatterns {
+		locs := cp.Regex.FindAllStringSubmatchIndex(text, -1)
+		for i := len(locs) - 1; i >= 0; i-- {
+			loc := locs[i]
+			gs, ge := 2*cp.Group, 2*cp.Group+1
+			if len(loc) <= ge || loc[gs] < 0 {
+				continue
 			}
-			return match
-		})
+			secret := text[loc[gs]:loc[ge]]
+			structure := describeStructure(secret)
+			recordAudit(events, lineNum, loc[gs], loc[ge]-loc[gs], cp.Label, "pattern", structure, 0, "")
+			prefix := text[loc[2]:loc[3]]
+			marker := withHash("[REDACTED:"+cp.Label+":"+structure+"]", secret, redact)
+			text = text[:loc[0]] + prefix + marker + text[loc[1]:]
+		}
 	}
 
 	// Git credential URLs
-	text = gitCredentialPattern.ReplaceAllStringFunc(text, func(match string) string {
-		submatches := gitCredentialPattern.FindStringSubmatch(match)
-		if len(submatches) >= 4 {
-			structure := describeStructure(submatches[2])
-			return submatches[1] + "[REDACTED:GIT_CREDENTIAL:" + structure + "]" + submatches[3]
+	locs := gitCredentialPattern.FindAllStringSubmatchIndex(text, -1)
+	for i := len(locs) - 1; i >= 0; i-- {
+		loc := locs[i]
+		if len(loc) < 8 {
+			continue
 		}
-		return match
-	})
+		secret := text[loc[4]:loc[5]]
+		structure := describeStructure(secret)
+		recordAudit(events, lineNum, loc[4], loc[5]-loc[4], "GIT_CREDENTIAL", "pattern", structure, 0, "")
+		marker := withHash("[REDACTED:GIT_CREDENTIAL:"+structure+"]", secret, redact)
+		text = text[:loc[0]] + text[loc[2]:loc[3]] + marker + text[loc[6]:loc[7]] + text[loc[1]:]
+	}
 
 	// Docker config auth
-	text = dockerAuthPattern.ReplaceAllStringFunc(text, func(match string) string {
-		submatches := dockerAuthPattern.FindStringSubmatch(match)
-		if len(submatches) >= 4 {
-			structure := describeStructure(submatches[2])
-			return submatches[1] + "[REDACTED:DOCKER_AUTH:" + structure + "]" + submatches[3]
+	locs = dockerAuthPattern.FindAllStringSubmatchIndex(text, -1)
+	for i := len(locs) - 1; i >= 0; i-- {
+		loc := locs[i]
+		if len(loc) < 8 {
+			continue
 		}
-		return match
-	})
+		secret := text[loc[4]:loc[5]]
+		structure := describeStructure(secret)
+		recordAudit(events, lineNum, loc[4], loc[5]-loc[4], "DOCKER_AUTH", "pattern", structure, 0, "")
+		marker := withHash("[REDACTED:DOCKER_AUTH:"+structure+"]", secret, redact)
+		text = text[:loc[0]] + text[loc[2]:loc[3]] + marker + text[loc[6]:loc[7]] + text[loc[1]:]
+	}
 
 	return text
 }
@@ -570,7 +648,10 @@ func getCompiledExclusions() []struct {
 	label           string
 	contextKeywords []string
 } {
+	configMu.Lock()
+	defer configMu.Unlock()
 	if !exclusionsCompiled {
+		compiledExclusions = nil
 		for _, excl := range EntropyExclusions {
 			pattern := excl.Pattern
 			if excl.CaseInsensitive {
@@ -642,16 +723,13 @@ func describeEntropyStructure(token string, entropy float64, charset string) str
 
 // getEntropyConfig gets entropy configuration with environment variable overrides
 func getEntropyConfig() EntropyConfig {
+	configMu.RLock()
 	config := EntropyConfig{
-		Thresholds: make(map[string]float64),
+		Thresholds: copyFloatMap(EntropyThresholds),
 		MinLength:  EntropyMinLength,
 		MaxLength:  EntropyMaxLength,
 	}
-
-	// Copy default thresholds
-	for k, v := range EntropyThresholds {
-		config.Thresholds[k] = v
-	}
+	configMu.RUnlock()
 
 	// Check for global threshold override
 	if val := os.Getenv("SECRETS_FILTER_ENTROPY_THRESHOLD"); val != "" {
@@ -695,7 +773,7 @@ func getEntropyConfig() EntropyConfig {
 }
 
 // redactEntropy detects and redacts high-entropy strings
-func redactEntropy(text string, config EntropyConfig) string {
+func redactEntropy(text string, config EntropyConfig, lineNum int, events *[]AuditEvent, redact RedactFlags) string {
 	tokens := extractTokens(text, config.MinLength, config.MaxLength)
 
 	// Process in reverse order to preserve positions when replacing
@@ -735,7 +813,8 @@ func redactEntropy(text string, config EntropyConfig) string {
 
 		if entropy >= threshold {
 			structure := describeEntropyStructure(token.Value, entropy, charset)
-			repl := "[REDACTED:HIGH_ENTROPY:" + structure + "]"
+			recordAudit(events, lineNum, token.Start, token.End-token.Start, "HIGH_ENTROPY", "entropy", structure, entropy, charset)
+			repl := withHash("[REDACTED:HIGH_ENTROPY:"+structure+"]", token.Value, redact)
 			replacements = append(replacements, replacement{
 				start: token.Start,
 				end:   token.End,
@@ -752,22 +831,98 @@ func redactEntropy(text string, config EntropyConfig) string {
 	return text
 }
 
-// redactLine applies all redaction to a single line based on config
-func redactLine(line string, secrets map[string]string, config FilterConfig, entropyConfig EntropyConfig) string {
+// redactLine applies all redaction to a single line based on config.
+// Entropy config is fetched fresh on every call (rather than once at
+// startup) so a SIGHUP config reload takes effect on the next line without
+// restarting the process. lineNum and events feed the optional audit
+// sidecar (see audit.go); events is nil when no sidecar was requested.
+func redactLine(line string, secrets map[string]string, config FilterConfig, lineNum int, events *[]AuditEvent, redact RedactFlags) string {
 	if config.ValuesEnabled && secrets != nil {
-		line = redactEnvValues(line, secrets)
+		line = redactEnvValues(line, secrets, lineNum, events, redact)
 	}
 	if config.PatternsEnabled {
-		line = redactPatterns(line)
+		line = redactPatterns(line, lineNum, events, redact)
 	}
 	if config.EntropyEnabled {
-		line = redactEntropy(line, entropyConfig)
+		line = redactEntropy(line, getEntropyConfig(), lineNum, events, redact)
 	}
 	return line
 }
 
+// emitLine redacts one line and flushes any audit events it produced to the
+// sidecar before returning the redacted text, so the --audit stream stays
+// in lockstep with stdout instead of batching until EOF.
+func emitLine(line string, secrets map[string]string, config FilterConfig, lineNum int, audit *auditWriter, redact RedactFlags) string {
+	var events []AuditEvent
+	redacted := redactLine(line, secrets, config, lineNum, &events, redact)
+	audit.flush(events)
+	return redacted
+}
+
+// emitPrivateKeyRedaction records the audit event for a whole-block
+// redaction under label (e.g. "PRIVATE_KEY", "OPENSSH_PRIVATE_KEY"), which
+// bypasses redactLine entirely.
+func emitPrivateKeyRedaction(lineNum int, audit *auditWriter, label string) {
+	var events []AuditEvent
+	recordAudit(&events, lineNum, 0, 0, label, "pattern", "multiline", 0, "")
+	audit.flush(events)
+}
+
+// maxPrivateKeyBuffer returns the active private-key buffer size limit,
+// which a SIGHUP config reload may have changed.
+func maxPrivateKeyBuffer() int {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return MaxPrivateKeyBuffer
+}
+
+// overflowAbandonMultiple bounds how much further than maxPrivateKeyBuffer
+// an unclosed block's buffer is allowed to grow in StateInPrivateKeyOverflow
+// before the scanner gives up waiting for its detector's End and resumes
+// normal filtering. Real blocks (PEM/OpenSSH/PGP armor, a wrapped JWT, a
+// kubeconfig blob, a pretty-printed service-account object) are already
+// well under maxPrivateKeyBuffer lines long in practice; this just gives
+// them generous extra room before treating the block as malformed or
+// intentionally unterminated. Without a cap here, a block whose End never
+// fires (an unclosed JSON object, a JWT wrap that never completes) grows
+// buffer without bound and silently swallows the rest of the stream.
+const overflowAbandonMultiple = 2
+
+// overflowAbandonLimit returns the line count at which an overflowing block
+// is abandoned rather than waited on further.
+func overflowAbandonLimit() int {
+	return maxPrivateKeyBuffer() * overflowAbandonMultiple
+}
+
 func main() {
+	applyKahlConfig(nil)
+	configPath := resolveKahlConfigPath(os.Args[1:])
+	if cfg, err := loadKahlConfig(configPath); err != nil {
+		fmt.Fprintf(os.Stderr, "kahl: %v\n", err)
+	} else {
+		applyKahlConfig(cfg)
+	}
+	watchConfigReload(configPath)
+
+	// Subcommand dispatch: "scan" walks a working tree (or the staged
+	// diff) instead of filtering stdin; everything else keeps the
+	// original stdin-filtering behavior so existing pipelines don't break.
+	if args := os.Args[1:]; len(args) > 0 && args[0] == "scan" {
+		runKahlScan(args[1:])
+		return
+	}
+
 	config := parseFilterConfig()
+	redact := parseRedactFlags(os.Args[1:])
+
+	auditSink, err := openAuditSink(parseAuditFlags(os.Args[1:]))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "kahl: %v\n", err)
+	}
+	if auditSink != nil {
+		defer auditSink.Close()
+	}
+	audit := newAuditWriter(auditSink)
 
 	// Only load secrets if values filter is enabled
 	var secrets map[string]string
@@ -775,14 +930,11 @@ func main() {
 		secrets = loadSecrets()
 	}
 
-	// Only load entropy config if entropy filter is enabled
-	var entropyConfig EntropyConfig
-	if config.EntropyEnabled {
-		entropyConfig = getEntropyConfig()
-	}
-
 	state := StateNormal
 	var buffer []string
+	var activeDetector BlockDetector
+	var activeLabel string
+	lineNum := 0
 
 	reader := bufio.NewReader(os.Stdin)
 
@@ -794,6 +946,7 @@ func main() {
 		if err == io.EOF && line == "" {
 			break
 		}
+		lineNum++
 
 		// Handle line without newline at EOF
 		hasNewline := strings.HasSuffix(line, "\n")
@@ -802,7 +955,7 @@ func main() {
 		if bytes.Contains([]byte(line), []byte{0}) {
 			// Flush buffer
 			for _, l := range buffer {
-				fmt.Print(redactLine(l, secrets, config, entropyConfig))
+				fmt.Print(emitLine(l, secrets, config, lineNum, audit, redact))
 			}
 			buffer = nil
 			// Passthrough this line and rest
@@ -813,34 +966,60 @@ func main() {
 
 		switch state {
 		case StateNormal:
-			if config.PatternsEnabled && privateKeyBegin.MatchString(line) {
+			var detector BlockDetector
+			var label string
+			var ok bool
+			if config.PatternsEnabled {
+				detector, label, ok = beginBlock(line)
+			}
+			if ok {
 				state = StateInPrivateKey
+				activeDetector = detector
+				activeLabel = label
 				buffer = []string{line}
 			} else {
-				fmt.Print(redactLine(line, secrets, config, entropyConfig))
+				fmt.Print(emitLine(line, secrets, config, lineNum, audit, redact))
 			}
 
 		case StateInPrivateKey:
 			buffer = append(buffer, line)
 
-			if privateKeyEnd.MatchString(line) {
-				fmt.Println("[REDACTED:PRIVATE_KEY:multiline]")
+			if activeDetector.End(line, buffer) {
+				fmt.Print(activeDetector.Redact(buffer))
+				emitPrivateKeyRedaction(lineNum, audit, activeLabel)
 				buffer = nil
+				activeDetector = nil
 				state = StateNormal
-			} else if len(buffer) > MaxPrivateKeyBuffer {
+			} else if len(buffer) > maxPrivateKeyBuffer() {
 				// Buffer overflow - redact entirely (fail closed, don't leak)
-				fmt.Println("[REDACTED:PRIVATE_KEY:multiline]")
-				buffer = nil
-				// Transition to overflow state - consume remaining lines silently until END
+				fmt.Print(blockMarker(activeLabel))
+				emitPrivateKeyRedaction(lineNum, audit, activeLabel)
+				// Transition to overflow state - already-redacted content is never
+				// printed again, but buffer keeps growing so End still sees the
+				// real running buffer: several detectors (jsonServiceAccountDetector,
+				// kubeconfigClientKeyDetector, jwtWrappedDetector) read buf, not just
+				// line, to tell the block is done, and a nil buf either ends them
+				// prematurely (leaking the rest of the block) or never ends them at
+				// all (dropping the rest of the stream).
 				state = StateInPrivateKeyOverflow
 			}
 
 		case StateInPrivateKeyOverflow:
-			// Consume lines silently until END marker
-			if privateKeyEnd.MatchString(line) {
+			// Consume lines silently until the active detector's End fires
+			buffer = append(buffer, line)
+			if activeDetector.End(line, buffer) {
+				buffer = nil
+				activeDetector = nil
+				state = StateNormal
+			} else if len(buffer) > overflowAbandonLimit() {
+				// The detector was given far more room than any real block
+				// should need and still hasn't found an end - give up on it
+				// rather than grow buffer forever and never print again.
+				buffer = nil
+				activeDetector = nil
 				state = StateNormal
 			}
-			// No buffering, no output - just wait for END
+			// No output - just wait for END (or abandonment)
 		}
 
 		if err == io.EOF {
@@ -851,14 +1030,15 @@ func main() {
 
 	// EOF: handle remaining state
 	if state == StateInPrivateKey {
-		// Incomplete private key block - redact entirely (fail closed, don't leak)
-		fmt.Println("[REDACTED:PRIVATE_KEY:multiline]")
+		// Incomplete block - redact entirely (fail closed, don't leak)
+		fmt.Print(blockMarker(activeLabel))
+		emitPrivateKeyRedaction(lineNum, audit, activeLabel)
 	} else if state == StateInPrivateKeyOverflow {
 		// Already emitted overflow redaction, nothing to do
 	} else if len(buffer) > 0 {
 		// Flush any remaining buffered content
 		for _, l := range buffer {
-			fmt.Print(redactLine(l, secrets, config, entropyConfig))
+			fmt.Print(emitLine(l, secrets, config, lineNum, audit, redact))
 		}
 	}
 }
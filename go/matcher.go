@@ -0,0 +1,208 @@
+// matcher.go: Aho-Corasick multi-pattern matching for kahl's known-value
+// lookups (env secrets, literal-string patterns).
+//
+// redactEnvValues used to sort secrets by length and run strings.Index once
+// per secret, an O(N*M) rescan of every line for N secrets. On a CI runner
+// exporting 50+ secret-looking env vars that adds up fast. Matcher instead
+// walks a line once against an automaton built from every known value,
+// emitting non-overlapping matches with longest-match-wins, so redactLine
+// stays roughly linear in line length regardless of how many secrets are
+// loaded.
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// matcherEntry is one literal value the automaton should recognize, paired
+// with the key it should be reported under (an env var name for secrets, a
+// pattern label for literal patterns).
+type matcherEntry struct {
+	Key   string
+	Value string
+}
+
+// Match is one non-overlapping occurrence found by Matcher.Scan.
+type Match struct {
+	Start, End int
+	Key        string
+	Value      string
+}
+
+// acNode is one state in the automaton's trie/failure-function graph.
+type acNode struct {
+	children map[byte]*acNode
+	fail     *acNode
+	out      []int // indices into Matcher.entries for values ending here
+}
+
+func newACNode() *acNode {
+	return &acNode{children: make(map[byte]*acNode)}
+}
+
+// Matcher is a built Aho-Corasick automaton over a fixed set of entries.
+type Matcher struct {
+	root    *acNode
+	entries []matcherEntry
+}
+
+// buildMatcher builds an automaton over entries, or returns nil if entries
+// is empty (or all values are empty) -- callers fall back to scanning
+// per-entry when Matcher is nil.
+func buildMatcher(entries []matcherEntry) *Matcher {
+	m := &Matcher{root: newACNode()}
+	for _, e := range entries {
+		if e.Value == "" {
+			continue
+		}
+		m.entries = append(m.entries, e)
+	}
+	if len(m.entries) == 0 {
+		return nil
+	}
+
+	for i, e := range m.entries {
+		node := m.root
+		for j := 0; j < len(e.Value); j++ {
+			c := e.Value[j]
+			next, ok := node.children[c]
+			if !ok {
+				next = newACNode()
+				node.children[c] = next
+			}
+			node = next
+		}
+		node.out = append(node.out, i)
+	}
+	m.buildFailureLinks()
+	return m
+}
+
+// buildFailureLinks runs the standard breadth-first Aho-Corasick
+// construction, linking each node to the longest proper suffix of its
+// prefix that is also a prefix in the trie, and merging output sets along
+// the way so a match of a shorter entry ending at the same position as a
+// longer one is still reported.
+func (m *Matcher) buildFailureLinks() {
+	var queue []*acNode
+	for _, child := range m.root.children {
+		child.fail = m.root
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for c, child := range node.children {
+			queue = append(queue, child)
+			fail := node.fail
+			for fail != nil {
+				if next, ok := fail.children[c]; ok {
+					child.fail = next
+					break
+				}
+				fail = fail.fail
+			}
+			if child.fail == nil {
+				child.fail = m.root
+			}
+			child.out = append(child.out, child.fail.out...)
+		}
+	}
+}
+
+// Scan walks line once and returns non-overlapping matches in left-to-right
+// order. When candidates overlap, the one starting earliest wins; among
+// candidates starting at the same position, the longest wins -- matching
+// the previous length-desc-sorted ReplaceAll behavior.
+func (m *Matcher) Scan(line string) []Match {
+	if m == nil {
+		return nil
+	}
+
+	type candidate struct{ start, end, idx int }
+	var candidates []candidate
+
+	node := m.root
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		for node != m.root {
+			if _, ok := node.children[c]; ok {
+				break
+			}
+			node = node.fail
+		}
+		if next, ok := node.children[c]; ok {
+			node = next
+		} else {
+			node = m.root
+		}
+		for _, idx := range node.out {
+			length := len(m.entries[idx].Value)
+			candidates = append(candidates, candidate{i + 1 - length, i + 1, idx})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].start != candidates[j].start {
+			return candidates[i].start < candidates[j].start
+		}
+		return (candidates[i].end - candidates[i].start) > (candidates[j].end - candidates[j].start)
+	})
+
+	var matches []Match
+	lastEnd := -1
+	for _, c := range candidates {
+		if c.start < lastEnd {
+			continue
+		}
+		e := m.entries[c.idx]
+		matches = append(matches, Match{Start: c.start, End: c.end, Key: e.Key, Value: e.Value})
+		lastEnd = c.end
+	}
+	return matches
+}
+
+// buildSecretMatcher builds a Matcher keyed by env var name over secrets'
+// values, for redactEnvValues.
+func buildSecretMatcher(secrets map[string]string) *Matcher {
+	entries := make([]matcherEntry, 0, len(secrets))
+	for k, v := range secrets {
+		entries = append(entries, matcherEntry{Key: k, Value: v})
+	}
+	return buildMatcher(entries)
+}
+
+// regexMetachars are the characters that, if present in a Pattern's source,
+// mean it isn't a plain literal string.
+const regexMetachars = `\.+*?()|[]{}^$`
+
+// literalPatternValue reports the fixed string re matches, if re has no
+// regex metacharacters in its source -- e.g. a user rule like
+// "regex: Company-Internal-Marker" rather than a character class or
+// quantifier.
+func literalPatternValue(re *regexp.Regexp) (string, bool) {
+	src := re.String()
+	if src == "" || strings.ContainsAny(src, regexMetachars) {
+		return "", false
+	}
+	return src, true
+}
+
+// splitLiteralPatterns partitions ps into the patterns that still need
+// regex matching and a Matcher over the ones that are plain literal
+// strings, so redactPatterns can scan literals in one automaton pass
+// instead of a full regex scan per pattern.
+func splitLiteralPatterns(ps []Pattern) ([]Pattern, *Matcher) {
+	var regexPatterns []Pattern
+	var entries []matcherEntry
+	for _, p := range ps {
+		if val, ok := literalPatternValue(p.Regex); ok {
+			entries = append(entries, matcherEntry{Key: p.Label, Value: val})
+			continue
+		}
+		regexPatterns = append(regexPatterns, p)
+	}
+	return regexPatterns, buildMatcher(entries)
+}
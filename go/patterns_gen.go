@@ -0,0 +1,179 @@
+// patterns_gen.go: compiled-in detection rules and defaults for kahl.
+//
+// This mirrors what the project's rule-pack generator produces (see the
+// "kahl.go patterns_gen.go" build line at the top of main.go); it ships as
+// a plain source file rather than output of `go generate` in this tree.
+// patterns/contextPatterns/EntropyExclusions/EntropyThresholds/
+// EntropyMinLength/EntropyMaxLength/MaxPrivateKeyBuffer below are the
+// *active* tables consulted by main.go's redaction engines. config.go
+// re-seeds them from the builtin* values here plus any user-supplied
+// config on startup and on SIGHUP.
+package main
+
+import "regexp"
+
+// Pattern holds a compiled regex and its label.
+type Pattern struct {
+	Regex *regexp.Regexp
+	Label string
+}
+
+// ContextPattern is a pattern whose match carries a prefix capture group
+// alongside the secret, used to simulate lookbehind.
+type ContextPattern struct {
+	Regex *regexp.Regexp
+	Label string
+	Group int
+}
+
+// EntropyExclusion describes a known non-secret high-entropy shape (git
+// SHAs, UUIDs, ...) that the entropy detector should skip, optionally only
+// when none of ContextKeywords precede the match.
+type EntropyExclusion struct {
+	Pattern         string
+	CaseInsensitive bool
+	Label           string
+	ContextKeywords []string
+}
+
+// builtinPatterns is the compiled-in rule set.
+var builtinPatterns = []Pattern{
+	// GitHub
+	{regexp.MustCompile(`ghp_[A-Za-z0-9]{36}`), "GITHUB_PAT"},
+	{regexp.MustCompile(`gho_[A-Za-z0-9]{36}`), "GITHUB_OAUTH"},
+	{regexp.MustCompile(`ghs_[A-Za-z0-9]{36}`), "GITHUB_SERVER"},
+	{regexp.MustCompile(`ghr_[A-Za-z0-9]{36}`), "GITHUB_REFRESH"},
+	{regexp.MustCompile(`github_pat_[A-Za-z0-9_]{22,}`), "GITHUB_PAT"},
+
+	// GitLab
+	{regexp.MustCompile(`glpat-[A-Za-z0-9_-]{20,}`), "GITLAB_PAT"},
+
+	// Slack
+	{regexp.MustCompile(`xoxb-[0-9]+-[0-9A-Za-z-]+`), "SLACK_BOT"},
+	{regexp.MustCompile(`xoxp-[0-9]+-[0-9A-Za-z-]+`), "SLACK_USER"},
+	{regexp.MustCompile(`xoxa-[0-9]+-[0-9A-Za-z-]+`), "SLACK_APP"},
+	{regexp.MustCompile(`xoxs-[0-9]+-[0-9A-Za-z-]+`), "SLACK_SESSION"},
+
+	// OpenAI / Anthropic
+	{regexp.MustCompile(`sk-[A-Za-z0-9]{48}`), "OPENAI_KEY"},
+	{regexp.MustCompile(`sk-proj-[A-Za-z0-9_-]{20,}`), "OPENAI_PROJECT_KEY"},
+	{regexp.MustCompile(`sk-ant-[A-Za-z0-9-]{90,}`), "ANTHROPIC_KEY"},
+
+	// AWS
+	{regexp.MustCompile(`AKIA[A-Z0-9]{16}`), "AWS_ACCESS_KEY"},
+
+	// Google Cloud
+	{regexp.MustCompile(`AIza[A-Za-z0-9_-]{35}`), "GOOGLE_API_KEY"},
+
+	// Stripe
+	{regexp.MustCompile(`sk_live_[A-Za-z0-9]{24,}`), "STRIPE_SECRET"},
+	{regexp.MustCompile(`sk_test_[A-Za-z0-9]{24,}`), "STRIPE_TEST"},
+	{regexp.MustCompile(`pk_live_[A-Za-z0-9]{24,}`), "STRIPE_PUBLISHABLE"},
+
+	// SendGrid
+	{regexp.MustCompile(`SG\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`), "SENDGRID_KEY"},
+
+	// npm / PyPI
+	{regexp.MustCompile(`npm_[A-Za-z0-9]{36}`), "NPM_TOKEN"},
+	{regexp.MustCompile(`pypi-[A-Za-z0-9_-]{100,}`), "PYPI_TOKEN"},
+
+	// JWT tokens
+	{regexp.MustCompile(`eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`), "JWT_TOKEN"},
+}
+
+// builtinContextPatterns is the compiled-in context-pattern set.
+var builtinContextPatterns = []ContextPattern{
+	{regexp.MustCompile(`(password=)([^\s,;"'\}\[\]]+)`), "PASSWORD_VALUE", 2},
+	{regexp.MustCompile(`(password:)(\s*[^\s,;"'\}\[\]]+)`), "PASSWORD_VALUE", 2},
+	{regexp.MustCompile(`(secret=)([^\s,;"'\}\[\]]+)`), "SECRET_VALUE", 2},
+	{regexp.MustCompile(`(secret:)(\s*[^\s,;"'\}\[\]]+)`), "SECRET_VALUE", 2},
+	{regexp.MustCompile(`(token=)([^\s,;"'\}\[\]]+)`), "TOKEN_VALUE", 2},
+	{regexp.MustCompile(`(token:)(\s*[^\s,;"'\}\[\]]+)`), "TOKEN_VALUE", 2},
+}
+
+var (
+	gitCredentialPattern = regexp.MustCompile(`(://[^:]+:)([^@]+)(@)`)
+	dockerAuthPattern    = regexp.MustCompile(`("auth":\s*")([A-Za-z0-9+/=]{20,})(")`)
+	privateKeyBegin      = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)
+	privateKeyEnd        = regexp.MustCompile(`-----END [A-Z ]*PRIVATE KEY-----`)
+)
+
+// Compiled-in defaults for the buffer-size and structure-description knobs
+// that config.go lets operators override.
+const (
+	builtinLongThreshold       = 50
+	builtinMaxPrivateKeyBuffer = 100
+	builtinEntropyMinLength    = 20
+	builtinEntropyMaxLength    = 4096
+)
+
+var builtinEntropyThresholds = map[string]float64{
+	"hex":          3.0,
+	"base64":       4.5,
+	"alphanumeric": 4.0,
+}
+
+var builtinEntropyExclusions = []EntropyExclusion{
+	{Pattern: `[0-9a-f]{40}`, CaseInsensitive: true, Label: "GIT_SHA", ContextKeywords: []string{"commit "}},
+	{Pattern: `[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`, CaseInsensitive: true, Label: "UUID"},
+}
+
+// EntropyContextKeywords are context keywords that, found near an
+// otherwise-unlabeled high-entropy token, flag it as likely secret-shaped
+// (see matchesExclusion's global-keyword fallback in main.go). This set is
+// compiled-in only; config.go doesn't currently expose it as a knob.
+var EntropyContextKeywords = map[string]bool{
+	"secret": true, "token": true, "password": true, "key": true, "credential": true,
+}
+
+func copyFloatMap(m map[string]float64) map[string]float64 {
+	out := make(map[string]float64, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// patterns, contextPatterns, EntropyExclusions, EntropyThresholds,
+// EntropyMinLength, EntropyMaxLength, and MaxPrivateKeyBuffer are the
+// active tables/knobs read by main.go's redaction engines. They start out
+// as copies of the builtin values above and are re-seeded by
+// applyKahlConfig (config.go) once config has been loaded.
+var (
+	patterns              = append([]Pattern(nil), builtinPatterns...)
+	contextPatterns       = append([]ContextPattern(nil), builtinContextPatterns...)
+	EntropyExclusions     = append([]EntropyExclusion(nil), builtinEntropyExclusions...)
+	EntropyThresholds     = copyFloatMap(builtinEntropyThresholds)
+	EntropyMinLength      = builtinEntropyMinLength
+	EntropyMaxLength      = builtinEntropyMaxLength
+	LongThreshold         = builtinLongThreshold
+	MaxPrivateKeyBuffer   = builtinMaxPrivateKeyBuffer
+	EntropyEnabledDefault = false
+)
+
+// defaultFilterConfig is the compiled-in filter baseline; config.go may
+// override it, and parseFilterConfig layers CLI/env on top of whichever is
+// active.
+var defaultFilterConfig = FilterConfig{
+	ValuesEnabled:   true,
+	PatternsEnabled: true,
+	EntropyEnabled:  false,
+}
+
+// explicitEnvVars lists environment variable names that are always treated
+// as secret-valued, regardless of suffix.
+var explicitEnvVars = map[string]bool{
+	"GITHUB_TOKEN": true, "GH_TOKEN": true, "GITLAB_TOKEN": true,
+	"AWS_SECRET_ACCESS_KEY": true, "AWS_SESSION_TOKEN": true, "AZURE_CLIENT_SECRET": true,
+	"OPENAI_API_KEY": true, "ANTHROPIC_API_KEY": true,
+	"SLACK_TOKEN": true, "SLACK_BOT_TOKEN": true,
+	"NPM_TOKEN": true, "PYPI_TOKEN": true, "DOCKER_PASSWORD": true,
+	"DATABASE_URL": true, "REDIS_URL": true, "MONGODB_URI": true,
+	"JWT_SECRET": true, "SESSION_SECRET": true, "ENCRYPTION_KEY": true,
+}
+
+// envSuffixes lists environment variable name suffixes that mark a
+// variable as secret-valued.
+var envSuffixes = []string{
+	"_SECRET", "_PASSWORD", "_PASSWD", "_TOKEN", "_API_KEY", "_PRIVATE_KEY", "_AUTH", "_CREDENTIAL", "_CREDENTIALS",
+}
@@ -0,0 +1,74 @@
+// redact.go: optional keyed-hash placeholders (--redact-mode=hash)
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strings"
+)
+
+// hmacSuffixLen is how many hex characters of the HMAC are kept in the
+// placeholder; enough to correlate occurrences without ballooning output.
+const hmacSuffixLen = 12
+
+// RedactFlags holds the opt-in --redact-mode / SECRETS_REDACT_MODE
+// configuration. The zero value is the existing bare-marker behavior.
+type RedactFlags struct {
+	Mode string // "" (default) or "hash"
+}
+
+// parseRedactFlags parses --redact-mode=hash from args, falling back to
+// SECRETS_REDACT_MODE when no flag is given.
+func parseRedactFlags(args []string) RedactFlags {
+	flags := RedactFlags{Mode: os.Getenv("SECRETS_REDACT_MODE")}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--redact-mode=") {
+			flags.Mode = strings.TrimPrefix(arg, "--redact-mode=")
+		}
+	}
+	return flags
+}
+
+// hmacKey is the per-process key used to compute hash-mode placeholders. It
+// defaults to a random salt, generated once at startup, so hashes never
+// correlate across separate invocations of the tool. Set KAHL_HMAC_KEY to a
+// fixed value to get stable hashes across runs instead, e.g. to trace the
+// same secret across multiple services' filtered logs.
+var hmacKey = initHMACKey()
+
+func initHMACKey() []byte {
+	if k := os.Getenv("KAHL_HMAC_KEY"); k != "" {
+		return []byte(k)
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		// crypto/rand failing is effectively unrecoverable on any real
+		// system; fall back to a fixed key rather than aborting a filter
+		// that's expected to keep streaming regardless.
+		return []byte("kahl-fallback-hmac-key")
+	}
+	return key
+}
+
+// withHash appends a keyed-HMAC hash suffix to an already-built
+// "[REDACTED:...]" marker when hash mode is enabled, so operators can tell
+// two redactions came from the same underlying secret without the value
+// ever being recoverable from the suffix. It's a no-op in the default mode.
+func withHash(marker, secret string, flags RedactFlags) string {
+	if flags.Mode != "hash" {
+		return marker
+	}
+	return marker[:len(marker)-1] + ":h=" + hmacHex(secret) + "]"
+}
+
+// hmacHex returns a truncated hex-encoded HMAC-SHA256 of secret keyed by
+// hmacKey.
+func hmacHex(secret string) string {
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write([]byte(secret))
+	sum := hex.EncodeToString(mac.Sum(nil))
+	return sum[:hmacSuffixLen]
+}
@@ -0,0 +1,296 @@
+// scan.go: "secrets-filter scan" recursive file/directory scanning mode
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ScanFlags holds the options for the "scan" subcommand.
+type ScanFlags struct {
+	Paths         []string
+	Jobs          int
+	InPlace       bool
+	IncludeBinary bool
+	Report        ReportFlags
+}
+
+// parseScanFlags parses the "scan" subcommand's args: everything that
+// isn't a recognized flag is treated as a path to scan.
+func parseScanFlags(args []string) ScanFlags {
+	flags := ScanFlags{Jobs: runtime.NumCPU(), Report: parseReportFlags(args)}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--in-place":
+			flags.InPlace = true
+		case arg == "--include-binary":
+			flags.IncludeBinary = true
+		case strings.HasPrefix(arg, "--jobs="):
+			if n, err := strconv.Atoi(strings.TrimPrefix(arg, "--jobs=")); err == nil && n > 0 {
+				flags.Jobs = n
+			}
+		case arg == "--jobs" && i+1 < len(args):
+			if n, err := strconv.Atoi(args[i+1]); err == nil && n > 0 {
+				flags.Jobs = n
+			}
+			i++
+		case strings.HasPrefix(arg, "--report") || strings.HasPrefix(arg, "--config"):
+			// Already consumed by parseReportFlags/loadConfig; also skip the
+			// separated-value form's value token here.
+			if !strings.Contains(arg, "=") && i+1 < len(args) {
+				i++
+			}
+		default:
+			if !strings.HasPrefix(arg, "-") {
+				flags.Paths = append(flags.Paths, arg)
+			}
+		}
+	}
+
+	if len(flags.Paths) == 0 {
+		flags.Paths = []string{"."}
+	}
+	return flags
+}
+
+// runScan implements "secrets-filter scan <paths...>".
+func runScan(args []string) {
+	cfg, err := loadConfig(resolveConfigPath(args))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "secrets-filter: %v\n", err)
+	}
+	mergeConfig(cfg)
+
+	flags := parseScanFlags(args)
+	entropy := parseEntropyFlags(args)
+	verify := parseVerifyFlags(args)
+
+	ignore := loadGitignores(flags.Paths)
+
+	var files []string
+	for _, root := range flags.Paths {
+		files = append(files, walkScanTargets(root, cfg, ignore)...)
+	}
+
+	var (
+		mu       sync.Mutex
+		events   []RedactionEvent
+		scanned  int
+		findings int
+	)
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for w := 0; w < flags.Jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				fileEvents, err := scanFile(path, entropy, verify, flags.InPlace, flags.IncludeBinary)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "secrets-filter: %s: %v\n", path, err)
+					continue
+				}
+				mu.Lock()
+				scanned++
+				findings += len(fileEvents)
+				events = append(events, fileEvents...)
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, path := range files {
+		jobs <- path
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := writeReport(flags.Report, events); err != nil {
+		fmt.Fprintf(os.Stderr, "secrets-filter: %v\n", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "secrets-filter: scanned %d file(s), %d finding(s)\n", scanned, findings)
+	if findings > 0 {
+		os.Exit(1)
+	}
+}
+
+// scanFile runs the detection engines over one file, returning its
+// RedactionEvents. With InPlace set, the file is rewritten with redactions
+// applied via a temp file + atomic rename.
+func scanFile(path string, entropy EntropyFlags, verify VerifyFlags, inPlace, includeBinary bool) ([]RedactionEvent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !includeBinary && looksBinary(data) {
+		return nil, nil
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	// bufio.Scanner strips the line terminator, so whether the original
+	// file's last line had one has to be recovered from the raw bytes -
+	// otherwise --in-place would always add a trailing newline, rewriting
+	// files whose last line never had one even when nothing on it needed
+	// redacting.
+	endsWithNewline := len(data) == 0 || data[len(data)-1] == '\n'
+
+	lineNums := make([]int, len(lines))
+	for i := range lines {
+		lineNums[i] = i + 1
+	}
+
+	// Unlike the streaming stdin filter, the whole file is already in memory
+	// here, so cross-line AWS key/secret pairs can be found in one pass over
+	// the full line set rather than through a sliding window.
+	var events []RedactionEvent
+	pairAWSKeysAcrossLines(lines, lineNums, &events)
+
+	var out strings.Builder
+	for i, l := range lines {
+		redacted := redactLine(l+"\n", nil, entropy, lineNums[i], &events, verify)
+		if i == len(lines)-1 && !endsWithNewline {
+			redacted = strings.TrimSuffix(redacted, "\n")
+		}
+		out.WriteString(redacted)
+	}
+
+	for i := range events {
+		if events[i].File == "" {
+			events[i].File = path
+		}
+	}
+
+	if inPlace && len(events) > 0 {
+		if err := writeFileAtomic(path, out.String()); err != nil {
+			return events, err
+		}
+	}
+
+	return events, nil
+}
+
+// looksBinary applies the same null-byte heuristic as the stdin filter,
+// plus a magic-number sniff of the first 512 bytes via net/http's content
+// sniffer, so common binary formats without embedded NULs are still caught.
+func looksBinary(data []byte) bool {
+	if len(data) > 512 {
+		data = data[:512]
+	}
+	for _, b := range data {
+		if b == 0 {
+			return true
+		}
+	}
+	contentType := http.DetectContentType(data)
+	return !strings.HasPrefix(contentType, "text/")
+}
+
+// writeFileAtomic writes content to a temp file in the same directory as
+// path, then renames it into place so readers never see a partial write.
+func writeFileAtomic(path, content string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, ".secrets-filter-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, info.Mode()); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// walkScanTargets walks root, returning files that survive the config's
+// blacklisted_extensions/blacklisted_paths/exclude_paths and .gitignore.
+func walkScanTargets(root string, cfg *Config, ignore *gitignoreSet) []string {
+	var files []string
+
+	info, err := os.Stat(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "secrets-filter: %s: %v\n", root, err)
+		return nil
+	}
+	if !info.IsDir() {
+		files = append(files, root)
+		return files
+	}
+
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if ignore.matches(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isScanExcluded(path, cfg) || ignore.matches(path, false) {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+
+	return files
+}
+
+// isScanExcluded reports whether path should be skipped per the config's
+// blacklisted_extensions, blacklisted_paths, and exclude_paths.
+func isScanExcluded(path string, cfg *Config) bool {
+	if cfg == nil {
+		return false
+	}
+	ext := filepath.Ext(path)
+	for _, e := range cfg.Scan.BlacklistedExtensions {
+		if strings.EqualFold(e, ext) {
+			return true
+		}
+	}
+	for _, p := range cfg.Scan.BlacklistedPaths {
+		if strings.Contains(path, p) {
+			return true
+		}
+	}
+	for _, p := range cfg.Scan.ExcludePaths {
+		if strings.Contains(path, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+// verify.go: optional live-verification of detected credentials (--verify)
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VerifyResult is the outcome of checking whether a matched credential is
+// still live.
+type VerifyResult struct {
+	Active bool
+}
+
+// Verifier performs a single low-privilege read call to confirm whether a
+// matched credential is still active. Implementations must not retry and
+// must respect ctx's deadline.
+type Verifier interface {
+	Verify(ctx context.Context, secret string) (VerifyResult, error)
+}
+
+// verifiers maps a pattern label to the Verifier that can check it. Each
+// verifier registers itself from its own file's init(), so new providers
+// can be added without touching the core pipeline.
+var verifiers = map[string]Verifier{}
+
+func registerVerifier(label string, v Verifier) {
+	verifiers[label] = v
+}
+
+const verifyTimeout = 5 * time.Second
+
+var verifyHTTPClient = &http.Client{Timeout: verifyTimeout}
+
+// VerifyFlags holds the opt-in --verify / --verify-labels configuration.
+// Network calls only ever happen when Enabled is explicitly set: this tool
+// is a filter that could otherwise exfiltrate secrets during innocent grep
+// sessions.
+type VerifyFlags struct {
+	Enabled bool
+	Labels  map[string]bool // nil means "every registered verifier"
+}
+
+// parseVerifyFlags parses --verify and --verify-labels=A,B,C from args.
+func parseVerifyFlags(args []string) VerifyFlags {
+	flags := VerifyFlags{}
+	for _, arg := range args {
+		switch {
+		case arg == "--verify":
+			flags.Enabled = true
+		case strings.HasPrefix(arg, "--verify-labels="):
+			flags.Labels = parseLabelSet(strings.TrimPrefix(arg, "--verify-labels="))
+		}
+	}
+	return flags
+}
+
+func parseLabelSet(csv string) map[string]bool {
+	set := make(map[string]bool)
+	for _, part := range strings.Split(csv, ",") {
+		if l := strings.TrimSpace(part); l != "" {
+			set[l] = true
+		}
+	}
+	return set
+}
+
+// eligible reports whether label should be verified under these flags.
+func (f VerifyFlags) eligible(label string) bool {
+	if !f.Enabled {
+		return false
+	}
+	if f.Labels == nil {
+		return true
+	}
+	return f.Labels[label]
+}
+
+// verifyCache memoizes results by SHA-256 hash of the secret, so a token
+// repeated across many lines only triggers one network call per run.
+// verifyCacheMu guards it: "scan"'s --jobs worker pool calls verifyStatus
+// from multiple goroutines concurrently.
+var (
+	verifyCacheMu sync.RWMutex
+	verifyCache   = map[string]VerifyResult{}
+)
+
+// verifyStatus returns "active" or "revoked" if label was verified, or ""
+// if verification doesn't apply (disabled, no verifier registered, or the
+// verifier call itself failed).
+func verifyStatus(label, secret string, flags VerifyFlags) string {
+	if !flags.eligible(label) {
+		return ""
+	}
+	v, ok := verifiers[label]
+	if !ok {
+		return ""
+	}
+
+	hash := sha256Hex(secret)
+	verifyCacheMu.RLock()
+	cached, ok := verifyCache[hash]
+	verifyCacheMu.RUnlock()
+	if ok {
+		return statusString(cached)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+	defer cancel()
+	result, err := v.Verify(ctx, secret)
+	if err != nil {
+		return ""
+	}
+	verifyCacheMu.Lock()
+	verifyCache[hash] = result
+	verifyCacheMu.Unlock()
+	return statusString(result)
+}
+
+func statusString(r VerifyResult) string {
+	if r.Active {
+		return "active"
+	}
+	return "revoked"
+}
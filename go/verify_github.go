@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"net/http"
+)
+
+// githubVerifier confirms a GitHub PAT is still active via GET /user, the
+// lowest-privilege authenticated endpoint GitHub exposes.
+type githubVerifier struct{}
+
+func init() {
+	v := githubVerifier{}
+	registerVerifier("GITHUB_PAT", v)
+	registerVerifier("GITHUB_OAUTH", v)
+}
+
+func (githubVerifier) Verify(ctx context.Context, secret string) (VerifyResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/user", nil)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+secret)
+
+	resp, err := verifyHTTPClient.Do(req)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	defer resp.Body.Close()
+
+	return VerifyResult{Active: resp.StatusCode == http.StatusOK}, nil
+}
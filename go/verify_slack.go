@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// slackVerifier confirms a Slack token is still active via auth.test, which
+// only echoes back identity information and makes no state changes.
+type slackVerifier struct{}
+
+func init() {
+	v := slackVerifier{}
+	registerVerifier("SLACK_BOT", v)
+	registerVerifier("SLACK_USER", v)
+	registerVerifier("SLACK_APP", v)
+}
+
+func (slackVerifier) Verify(ctx context.Context, secret string) (VerifyResult, error) {
+	form := url.Values{"token": {secret}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://slack.com/api/auth.test", strings.NewReader(form.Encode()))
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := verifyHTTPClient.Do(req)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		OK bool `json:"ok"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return VerifyResult{}, err
+	}
+	return VerifyResult{Active: body.OK}, nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"net/http"
+)
+
+// stripeVerifier confirms a Stripe secret key is still active via
+// GET /v1/balance, a read-only endpoint available to every key's scope.
+type stripeVerifier struct{}
+
+func init() {
+	registerVerifier("STRIPE_SECRET", stripeVerifier{})
+}
+
+func (stripeVerifier) Verify(ctx context.Context, secret string) (VerifyResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.stripe.com/v1/balance", nil)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	req.SetBasicAuth(secret, "")
+
+	resp, err := verifyHTTPClient.Do(req)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	defer resp.Body.Close()
+
+	return VerifyResult{Active: resp.StatusCode == http.StatusOK}, nil
+}
+
+// AWS_ACCESS_KEY is intentionally not wired to a verifier here: STS
+// GetCallerIdentity needs the paired secret access key to compute a SigV4
+// signature, which a single AKIA… match never carries on its own. Once the
+// paired-credential detection lands (see the AWS_ACCESS_KEY_PAIR marker),
+// that combined match is what should drive AWS verification.